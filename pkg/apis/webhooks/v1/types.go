@@ -0,0 +1,64 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UidMapping associates a ServiceAccount in a given namespace with the
+// POSIX identity it should run as. It replaces the single cluster-wide
+// uid-mapping ConfigMap, letting cluster admins manage mappings per
+// namespace with RBAC, kubectl edit and validation schemas.
+type UidMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec UidMappingSpec `json:"spec"`
+}
+
+// UidMappingSpec is the desired POSIX identity for a ServiceAccount.
+type UidMappingSpec struct {
+	// ServiceAccount is the name of the ServiceAccount this mapping applies to.
+	ServiceAccount string `json:"serviceAccount"`
+	// Namespace is the namespace the ServiceAccount lives in. A UidMapping
+	// only applies to requests made by its own ServiceAccount/Namespace pair.
+	Namespace string `json:"namespace"`
+	// UID is the POSIX user ID to set as RunAsUser.
+	UID int64 `json:"uid"`
+	// GID, if set, is the POSIX group ID to set as RunAsGroup.
+	GID *int64 `json:"gid,omitempty"`
+	// SupplementalGroups lists additional group IDs to add to the pod's
+	// SecurityContext.
+	SupplementalGroups []int64 `json:"supplementalGroups,omitempty"`
+	// FSGroup, if set, is applied to the pod's SecurityContext.FSGroup.
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+	// SeccompProfile, if set, is applied to the pod's
+	// SecurityContext.SeccompProfile (e.g. "RuntimeDefault", "Unconfined" or
+	// "Localhost").
+	SeccompProfile string `json:"seccompProfile,omitempty"`
+	// SeccompLocalhostProfile names the profile file under the kubelet's
+	// seccomp root, and is only meaningful when SeccompProfile is "Localhost".
+	SeccompLocalhostProfile string `json:"seccompLocalhostProfile,omitempty"`
+	// AppArmorProfile, if set, is injected as the
+	// container.apparmor.security.beta.kubernetes.io/<container> annotation
+	// on every container (e.g. "runtime/default" or "localhost/<profile>").
+	AppArmorProfile string `json:"appArmorProfile,omitempty"`
+	// HostPID, if set, overrides the pod's Spec.HostPID.
+	HostPID *bool `json:"hostPID,omitempty"`
+	// HostIPC, if set, overrides the pod's Spec.HostIPC.
+	HostIPC *bool `json:"hostIPC,omitempty"`
+	// ShareProcessNamespace, if set, overrides the pod's
+	// Spec.ShareProcessNamespace.
+	ShareProcessNamespace *bool `json:"shareProcessNamespace,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UidMappingList is a list of UidMapping resources.
+type UidMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []UidMapping `json:"items"`
+}
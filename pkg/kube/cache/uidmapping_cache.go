@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	webhooksv1 "github.com/lucamiano/Webhooks/pkg/apis/webhooks/v1"
+	webhooksclientset "github.com/lucamiano/Webhooks/pkg/generated/clientset/versioned"
+)
+
+// resyncPeriod controls how often the informer re-lists from its local
+// store, independent of watch events.
+const resyncPeriod = 10 * time.Minute
+
+// byServiceAccountIndex indexes UidMapping objects by
+// "<object-namespace>/<serviceAccount>" so Mutate can look a mapping up
+// without scanning every object in the cache. The object's own
+// metadata.namespace is used rather than spec.namespace: spec.namespace is
+// attacker-controllable by anyone with create-UidMapping RBAC in any
+// namespace, and keying on it would let a tenant in namespace A plant a
+// mapping that gets applied to a ServiceAccount in namespace B.
+const byServiceAccountIndex = "byServiceAccount"
+
+// UidMappingCache maintains a cluster-wide SharedIndexInformer over the
+// UidMapping CRD and exposes a lookup by (namespace, serviceAccount), the
+// natural key cluster admins manage mappings with via kubectl.
+type UidMappingCache struct {
+	Logger logrus.FieldLogger
+
+	informer cache.SharedIndexInformer
+}
+
+// NewUidMappingCache builds the in-cluster clientset for the UidMapping CRD
+// once and constructs a SharedIndexInformer over all namespaces.
+func NewUidMappingCache(logger logrus.FieldLogger) (*UidMappingCache, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error getting in-cluster config: %s", err)
+	}
+
+	client, err := webhooksclientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error starting UidMapping client from config: %s", err)
+	}
+
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.WebhooksV1().UidMappings(metav1.NamespaceAll).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.WebhooksV1().UidMappings(metav1.NamespaceAll).Watch(context.TODO(), opts)
+			},
+		},
+		&webhooksv1.UidMapping{},
+		resyncPeriod,
+		cache.Indexers{byServiceAccountIndex: indexByServiceAccount},
+	)
+
+	return &UidMappingCache{Logger: logger, informer: informer}, nil
+}
+
+func indexByServiceAccount(obj interface{}) ([]string, error) {
+	mapping, ok := obj.(*webhooksv1.UidMapping)
+	if !ok {
+		return nil, fmt.Errorf("object is not a UidMapping")
+	}
+	// A mapping whose declared spec.namespace disagrees with where it was
+	// actually created is either stale or an attempt to target a
+	// ServiceAccount outside the author's own namespace. Either way it
+	// isn't indexed, so Get can never return it.
+	if mapping.Spec.Namespace != "" && mapping.Spec.Namespace != mapping.Namespace {
+		return nil, nil
+	}
+	return []string{mapping.Namespace + "/" + mapping.Spec.ServiceAccount}, nil
+}
+
+// Start runs the informer and blocks until its initial list/watch has
+// synced or stopCh is closed.
+func (c *UidMappingCache) Start(stopCh <-chan struct{}) error {
+	go c.informer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, c.informer.HasSynced) {
+		return fmt.Errorf("timed out waiting for UidMapping cache to sync")
+	}
+
+	c.Logger.Info("UidMapping cache synced")
+	return nil
+}
+
+// HasSynced reports whether the informer has completed its initial list.
+func (c *UidMappingCache) HasSynced() bool {
+	return c.informer.HasSynced()
+}
+
+// ReadinessHandler returns an http.HandlerFunc suitable for mounting as the
+// webhook server's readiness probe (e.g. at /readyz). It reports 503 until
+// HasSynced is true, so the API server won't route AdmissionReviews to this
+// instance before the initial UidMapping list has completed and Get can
+// return accurate results.
+func (c *UidMappingCache) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !c.HasSynced() {
+			http.Error(w, "UidMapping cache not yet synced", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Get returns the UidMapping created in namespace for serviceAccount, or
+// nil if no such mapping exists. The lookup is scoped to namespace via the
+// index key, so a mapping object created in a different namespace is never
+// returned, regardless of what its spec claims.
+func (c *UidMappingCache) Get(namespace, serviceAccount string) (*webhooksv1.UidMapping, error) {
+	items, err := c.informer.GetIndexer().ByIndex(byServiceAccountIndex, namespace+"/"+serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up UidMapping for %s/%s: %s", namespace, serviceAccount, err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	mapping := items[0].(*webhooksv1.UidMapping)
+	if mapping.Namespace != namespace {
+		return nil, fmt.Errorf("found UidMapping %s/%s indexed under unexpected namespace %s", namespace, serviceAccount, mapping.Namespace)
+	}
+	return mapping, nil
+}
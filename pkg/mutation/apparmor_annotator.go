@@ -0,0 +1,69 @@
+package mutation
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lucamiano/Webhooks/pkg/kube/cache"
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
+
+// appArmorAnnotationPrefix is the well-known pod annotation prefix the
+// kubelet reads AppArmor profiles from.
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// appArmorAnnotator injects container.apparmor.security.beta.kubernetes.io/<container>
+// annotations from the requesting ServiceAccount's UidMapping policy.
+type appArmorAnnotator struct {
+	Logger    logrus.FieldLogger
+	Cache     *cache.UidMappingCache
+	Validator *validation.Validator
+}
+
+// appArmorAnnotator implements the podMutator interface
+var _ podMutator = (*appArmorAnnotator)(nil)
+
+// Name returns the appArmorAnnotator short name
+func (aaa appArmorAnnotator) Name() string {
+	return "apparmor_annotator"
+}
+
+// Mutate returns a new pod with an AppArmor annotation set for every
+// container, according to the requesting ServiceAccount's UidMapping.
+func (aaa appArmorAnnotator) Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRequest) (*corev1.Pod, error) {
+	aaa.Logger = aaa.Logger.WithField("mutation", aaa.Name())
+	mpod := pod.DeepCopy()
+
+	namespace, serviceAccount := getServiceAccount(aaa.Logger, a)
+	if serviceAccount == "" {
+		return mpod, nil
+	}
+
+	mapping, err := aaa.Cache.Get(namespace, serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to look up UidMapping: %s\n", err)
+	}
+	if mapping == nil || mapping.Spec.AppArmorProfile == "" {
+		return mpod, nil
+	}
+
+	if !authorizedForUidMapping(aaa.Validator, aaa.Logger, a.UserInfo, namespace, serviceAccount, mapping.Name) {
+		return mpod, nil
+	}
+
+	if mpod.Annotations == nil {
+		mpod.Annotations = map[string]string{}
+	}
+	for _, container := range mpod.Spec.Containers {
+		mpod.Annotations[appArmorAnnotationPrefix+container.Name] = mapping.Spec.AppArmorProfile
+	}
+	for _, container := range mpod.Spec.InitContainers {
+		mpod.Annotations[appArmorAnnotationPrefix+container.Name] = mapping.Spec.AppArmorProfile
+	}
+
+	aaa.Logger.Infof("Applying AppArmor profile %s for ServiceAccount %s in namespace %s", mapping.Spec.AppArmorProfile, serviceAccount, namespace)
+	return mpod, nil
+}
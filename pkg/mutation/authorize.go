@@ -0,0 +1,34 @@
+package mutation
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
+
+// authorizedForUidMapping confirms, via validator, that userInfo is allowed
+// to use the named UidMapping resolved for namespace/serviceAccount. Every
+// mutator that reads a UidMapping and applies it to the pod goes through
+// this same check, since each mutates the pod based on attacker-controllable
+// identity claims, and binding the review to mappingName ensures the grant
+// is for this specific object rather than every UidMapping in namespace. A
+// nil validator (e.g. in tests) always authorizes.
+func authorizedForUidMapping(validator *validation.Validator, logger logrus.FieldLogger, userInfo authenticationv1.UserInfo, namespace, serviceAccount, mappingName string) bool {
+	if validator == nil {
+		return true
+	}
+
+	allowed, err := validator.Authorize(context.Background(), userInfo, namespace, "uidmappings", mappingName, "use")
+	if err != nil {
+		logger.Errorf("Failed to validate ServiceAccount %s in namespace %s: %s", serviceAccount, namespace, err)
+		return false
+	}
+	if !allowed {
+		logger.Warnf("ServiceAccount %s in namespace %s is not authorized to use UidMapping %s, skipping mutation", serviceAccount, namespace, mappingName)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package mutation
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lucamiano/Webhooks/pkg/kube/cache"
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
+
+// Dependencies bundles the shared collaborators every mutator in the chain
+// may need, constructed once at webhook startup.
+type Dependencies struct {
+	Logger    logrus.FieldLogger
+	Cache     *cache.UidMappingCache
+	Validator *validation.Validator
+	Config    HomeDirectoryConfig
+}
+
+// mutatorFactories maps a mutator's short name to a constructor, so the
+// enabled set can be selected by name via CLI flags or a config file.
+var mutatorFactories = map[string]func(Dependencies) podMutator{
+	"mount_home_directory": func(d Dependencies) podMutator {
+		return mountHomeDirectory{Logger: d.Logger, Cache: d.Cache, Config: d.Config, Validator: d.Validator}
+	},
+	"seccomp_profile_defaulter": func(d Dependencies) podMutator {
+		return seccompProfileDefaulter{Logger: d.Logger, Cache: d.Cache, Validator: d.Validator}
+	},
+	"apparmor_annotator": func(d Dependencies) podMutator {
+		return appArmorAnnotator{Logger: d.Logger, Cache: d.Cache, Validator: d.Validator}
+	},
+	"namespace_sharing_enforcer": func(d Dependencies) podMutator {
+		return namespaceSharingEnforcer{Logger: d.Logger, Cache: d.Cache, Validator: d.Validator}
+	},
+}
+
+// Chain runs an ordered, configurable set of mutators against an admitted
+// pod, accumulating each mutator's change into its own JSONPatch fragment
+// so the final AdmissionResponse.Patch is a single merged, deterministic
+// patch.
+type Chain struct {
+	Logger   logrus.FieldLogger
+	mutators []podMutator
+}
+
+// NewChain builds a Chain from an ordered list of mutator names, resolved
+// against mutatorFactories. Unknown names are rejected so misconfiguration
+// is caught at startup rather than silently ignored at admission time.
+func NewChain(logger logrus.FieldLogger, names []string, deps Dependencies) (*Chain, error) {
+	deps.Logger = logger
+
+	mutators := make([]podMutator, 0, len(names))
+	for _, name := range names {
+		factory, ok := mutatorFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown mutator %q", name)
+		}
+		mutators = append(mutators, factory(deps))
+	}
+
+	return &Chain{Logger: logger, mutators: mutators}, nil
+}
+
+// Mutate runs every mutator in the chain in order and returns the merged
+// JSONPatch describing all of their changes together.
+func (c *Chain) Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	var patch []PatchOperation
+	current := pod
+
+	for _, mutator := range c.mutators {
+		mutated, err := mutator.Mutate(current, a)
+		if err != nil {
+			return nil, fmt.Errorf("mutator %s failed: %s", mutator.Name(), err)
+		}
+
+		fragment, err := diffPatch(current, mutated)
+		if err != nil {
+			return nil, fmt.Errorf("mutator %s produced an undiffable pod: %s", mutator.Name(), err)
+		}
+
+		patch = append(patch, fragment...)
+		current = mutated
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,39 @@
+package mutation
+
+// HomeDirectoryConfig controls how mountHomeDirectory provisions a home
+// directory volume for the mapped ServiceAccount. It is populated from the
+// webhook's config file/flags at startup.
+type HomeDirectoryConfig struct {
+	// VolumeSource selects the kind of volume to inject: "hostPath" or "pvc".
+	VolumeSource string
+	// HostPathBase is the host directory under which per-ServiceAccount
+	// home directories are created when VolumeSource is "hostPath", e.g.
+	// "/home" yields "/home/<serviceAccount>".
+	HostPathBase string
+	// PVCClaimNameTemplate is a fmt template (one %s, the ServiceAccount
+	// name) naming the PersistentVolumeClaim to mount when VolumeSource is
+	// "pvc", e.g. "home-%s".
+	PVCClaimNameTemplate string
+	// MountPathTemplate is a fmt template (one %s, the ServiceAccount name)
+	// for the path the volume is mounted at in every container, e.g.
+	// "/home/%s".
+	MountPathTemplate string
+	// OptOutAnnotation, when present (with any value) on the pod, skips
+	// injecting the home directory volume/mounts entirely.
+	OptOutAnnotation string
+}
+
+// defaultHomeDirectoryConfig is used when the webhook's config does not
+// override these settings.
+var defaultHomeDirectoryConfig = HomeDirectoryConfig{
+	VolumeSource:      "hostPath",
+	HostPathBase:      "/home",
+	MountPathTemplate: "/home/%s",
+	OptOutAnnotation:  "webhooks.example.com/skip-home-volume",
+}
+
+// defaultEnabledMutators is the chain run when the webhook's CLI flags or
+// config file don't select one explicitly. It preserves the webhook's
+// original single-purpose behavior; the seccomp/AppArmor/namespace-sharing
+// mutators are opt-in.
+var defaultEnabledMutators = []string{"mount_home_directory"}
@@ -0,0 +1,94 @@
+package mutation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// homeVolumeName is the name given to the injected home directory volume.
+// Existing volumes/mounts with this name are left untouched on retry.
+const homeVolumeName = "home-directory"
+
+// injectHomeVolume adds a home directory volume for serviceAccount to the
+// pod, and a matching VolumeMount to every container and initContainer that
+// doesn't already declare a mount at that path. It is a no-op if the pod
+// carries the configured opt-out annotation.
+func injectHomeVolume(mpod *corev1.Pod, serviceAccount string, config HomeDirectoryConfig) error {
+	if config.OptOutAnnotation != "" {
+		if _, skip := mpod.Annotations[config.OptOutAnnotation]; skip {
+			return nil
+		}
+	}
+
+	mountPath := fmt.Sprintf(config.MountPathTemplate, serviceAccount)
+
+	volume, err := buildHomeVolume(serviceAccount, config)
+	if err != nil {
+		return err
+	}
+
+	if !hasVolume(mpod.Spec.Volumes, homeVolumeName) {
+		mpod.Spec.Volumes = append(mpod.Spec.Volumes, volume)
+	}
+
+	mount := corev1.VolumeMount{Name: homeVolumeName, MountPath: mountPath}
+	for i := range mpod.Spec.InitContainers {
+		addVolumeMount(&mpod.Spec.InitContainers[i], mount)
+	}
+	for i := range mpod.Spec.Containers {
+		addVolumeMount(&mpod.Spec.Containers[i], mount)
+	}
+
+	return nil
+}
+
+// buildHomeVolume constructs the Volume to mount, according to the
+// configured VolumeSource.
+func buildHomeVolume(serviceAccount string, config HomeDirectoryConfig) (corev1.Volume, error) {
+	switch config.VolumeSource {
+	case "pvc":
+		claimName := fmt.Sprintf(config.PVCClaimNameTemplate, serviceAccount)
+		return corev1.Volume{
+			Name: homeVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				},
+			},
+		}, nil
+	case "hostPath", "":
+		hostPathType := corev1.HostPathDirectoryOrCreate
+		return corev1.Volume{
+			Name: homeVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: fmt.Sprintf("%s/%s", config.HostPathBase, serviceAccount),
+					Type: &hostPathType,
+				},
+			},
+		}, nil
+	default:
+		return corev1.Volume{}, fmt.Errorf("unsupported home directory volume source: %s", config.VolumeSource)
+	}
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	for _, v := range volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addVolumeMount appends mount to container unless it already declares a
+// mount at the same path.
+func addVolumeMount(container *corev1.Container, mount corev1.VolumeMount) {
+	for _, existing := range container.VolumeMounts {
+		if existing.MountPath == mount.MountPath {
+			return
+		}
+	}
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+}
@@ -1,25 +1,30 @@
 package mutation
 
 import (
-	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	rest "k8s.io/client-go/rest"
-)
 
-var configMapName = "uid-mapping"
-var namespace = "default"
+	webhooksv1 "github.com/lucamiano/Webhooks/pkg/apis/webhooks/v1"
+	"github.com/lucamiano/Webhooks/pkg/kube/cache"
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
 
 // minLifespanTolerations is a container for mininum lifespan mutation
 type mountHomeDirectory struct {
 	Logger logrus.FieldLogger
+	// Cache is the shared informer-backed lister for the UidMapping CRD,
+	// constructed once at webhook startup.
+	Cache *cache.UidMappingCache
+	// Config controls how the home directory volume is provisioned and
+	// mounted. Defaults to defaultHomeDirectoryConfig when unset.
+	Config HomeDirectoryConfig
+	// Validator confirms the requesting identity is authorized to receive
+	// a UID mapping before it is applied.
+	Validator *validation.Validator
 }
 
 // minLifespanTolerations imhdements the podMutator interface
@@ -36,85 +41,81 @@ func (mhd mountHomeDirectory) Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRe
 	mhd.Logger = mhd.Logger.WithField("mutation", mhd.Name())
 	mpod := pod.DeepCopy()
 	securityContext := pod.Spec.SecurityContext
-	serviceAccount := getServiceAccount(mhd, a)
+	requestNamespace, serviceAccount := getServiceAccount(mhd.Logger, a)
 
 	if securityContext == nil || securityContext.RunAsUser == nil {
 		if serviceAccount != "" {
+			mapping, err := mhd.Cache.Get(requestNamespace, serviceAccount)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to set RunAsUser: %s", err)
+			}
+
+			// Bind the authorization check to the resolved mapping's name when
+			// one exists. There's no specific object to bind to when mapping is
+			// nil, so that case falls back to the namespace-scoped "uidmappings"
+			// check; this keeps unauthorized/unenrolled ServiceAccounts skipping
+			// mutation below rather than hitting the hard failure for a missing
+			// UidMapping.
+			mappingName := ""
+			if mapping != nil {
+				mappingName = mapping.Name
+			}
+			if !authorizedForUidMapping(mhd.Validator, mhd.Logger, a.UserInfo, requestNamespace, serviceAccount, mappingName) {
+				return mpod, nil
+			}
+
+			if mapping == nil {
+				return nil, fmt.Errorf("ServiceAccount %s in namespace %s has no UidMapping associated with it", serviceAccount, requestNamespace)
+			}
+
 			logMessage := fmt.Sprintf("No runAsUser rule found, applying default for current ServiceAccount %s", serviceAccount)
 			mhd.Logger.Info(logMessage)
 
-			var err error
-			mpod.Spec.SecurityContext, err = setUID(mhd, mpod.Spec.SecurityContext, serviceAccount)
-			if err != nil {
-				return nil, fmt.Errorf("Failed to set RunAsUser: %s\n", err)
+			mpod.Spec.SecurityContext = setUID(mhd, mpod.Spec.SecurityContext, mapping)
+
+			config := mhd.Config
+			if config.MountPathTemplate == "" {
+				config = defaultHomeDirectoryConfig
+			}
+			if err := injectHomeVolume(mpod, serviceAccount, config); err != nil {
+				return nil, fmt.Errorf("Failed to inject home directory volume: %s", err)
 			}
 		}
 	}
 	return mpod, nil
 }
 
-// Set RunAsUser field based on ServiceAccountName
-func setUID(mhd mountHomeDirectory, existing *corev1.PodSecurityContext, serviceAccount string) (*corev1.PodSecurityContext, error) {
-	client, err := initClient()
-	if err != nil {
-		logMessage := fmt.Sprintf("Failed initializing Kubernetes client: %s\n", err)
-		return nil, fmt.Errorf(logMessage)
-	}
-	configMap, err := getConfigMap(client)
-	if err != nil {
-		logMessage := fmt.Sprintf("Failed setting UID: %s\n", err)
-		return nil, fmt.Errorf(logMessage)
+// setUID populates RunAsUser, RunAsGroup, FSGroup and SupplementalGroups on
+// the pod's SecurityContext from the ServiceAccount's UidMapping.
+func setUID(mhd mountHomeDirectory, existing *corev1.PodSecurityContext, mapping *webhooksv1.UidMapping) *corev1.PodSecurityContext {
+	if existing == nil {
+		existing = &corev1.PodSecurityContext{}
 	}
-	data := configMap.Data
-	uid := data[serviceAccount]
 
-	if uid == "" {
-		logMessage := fmt.Sprintf("ServiceAccount %s\n has no UID associated with it", err)
-		return nil, fmt.Errorf(logMessage)
-	}
-	logMessage := fmt.Sprintf("ServiceAccount %s has UID %s associated with it", serviceAccount, uid)
+	logMessage := fmt.Sprintf("ServiceAccount %s in namespace %s has UID %d associated with it", mapping.Spec.ServiceAccount, mapping.Spec.Namespace, mapping.Spec.UID)
 	mhd.Logger.Info(logMessage)
-	uid64, err := strconv.ParseInt(data[serviceAccount], 10, 64)
 
-	if err != nil {
-		logMessage := fmt.Sprintf("Failed to convert UID to int64: %s", err)
-		return nil, fmt.Errorf(logMessage)
+	// mapping is read from the shared informer cache and must be treated as
+	// read-only: copy its pointers and slice into freshly allocated memory
+	// rather than aliasing cache-owned storage into the pod spec.
+	uid := mapping.Spec.UID
+	existing.RunAsUser = &uid
+	if mapping.Spec.GID != nil {
+		gid := *mapping.Spec.GID
+		existing.RunAsGroup = &gid
 	}
-	existing.RunAsUser = &uid64
-	return existing, nil
-}
-
-// Init Kubernetes Client to interact with the API
-func initClient() (*kubernetes.Clientset, error) {
-	// Init client from inside pod
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		logMessage := fmt.Sprintf("Error getting in-cluster config: %s\n", err)
-		return nil, fmt.Errorf(logMessage)
+	if mapping.Spec.SupplementalGroups != nil {
+		existing.SupplementalGroups = append([]int64(nil), mapping.Spec.SupplementalGroups...)
 	}
-
-	// Creating client
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		logMessage := fmt.Sprintf("Error starting Kubernetes client from config: %s\n", err)
-		return nil, fmt.Errorf(logMessage)
+	if mapping.Spec.FSGroup != nil {
+		fsGroup := *mapping.Spec.FSGroup
+		existing.FSGroup = &fsGroup
 	}
-	return clientset, nil
+	return existing
 }
 
-// Retrieve ConfigMap based on name and namespaces
-func getConfigMap(client *kubernetes.Clientset) (*corev1.ConfigMap, error) {
-	// Get ConfigMap
-	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
-	if err != nil {
-		logMessage := fmt.Sprintf("Error getting ConfigMap: %s\n", err)
-		return nil, fmt.Errorf(logMessage)
-	}
-	return configMap, nil
-}
-
-// Get ServiceAccount from API request
-func getServiceAccount(mhd mountHomeDirectory, request *admissionv1.AdmissionRequest) string {
+// Get namespace and ServiceAccount from API request
+func getServiceAccount(logger logrus.FieldLogger, request *admissionv1.AdmissionRequest) (string, string) {
 	userInfo := request.UserInfo
 	if userInfo.Username != "" && strings.HasPrefix(userInfo.Username, "system:serviceaccount:") {
 		parts := strings.Split(userInfo.Username, ":")
@@ -122,10 +123,10 @@ func getServiceAccount(mhd mountHomeDirectory, request *admissionv1.AdmissionReq
 			namespace := parts[2]
 			serviceAccountName := parts[3]
 			logMessage := fmt.Sprintf("Request made by ServiceAccount: %s in namespace: %s", serviceAccountName, namespace)
-			mhd.Logger.Info(logMessage)
+			logger.Info(logMessage)
 
-			return serviceAccountName
+			return namespace, serviceAccountName
 		}
 	}
-	return ""
+	return "", ""
 }
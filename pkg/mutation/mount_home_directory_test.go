@@ -0,0 +1,159 @@
+package mutation
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	webhooksv1 "github.com/lucamiano/Webhooks/pkg/apis/webhooks/v1"
+)
+
+func testMountHomeDirectory() mountHomeDirectory {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return mountHomeDirectory{Logger: logger}
+}
+
+func TestSetUIDCopiesGIDRatherThanAliasing(t *testing.T) {
+	gid := int64(2000)
+	mapping := &webhooksv1.UidMapping{
+		Spec: webhooksv1.UidMappingSpec{UID: 1000, GID: &gid},
+	}
+
+	result := setUID(testMountHomeDirectory(), nil, mapping)
+	if result.RunAsGroup == nil || *result.RunAsGroup != gid {
+		t.Fatalf("RunAsGroup = %v, want %d", result.RunAsGroup, gid)
+	}
+	if result.RunAsGroup == mapping.Spec.GID {
+		t.Fatalf("RunAsGroup aliases mapping.Spec.GID instead of a fresh pointer")
+	}
+
+	// Mutating the returned SecurityContext must not affect the cached
+	// mapping, since the cache is shared across concurrent admission
+	// requests.
+	*result.RunAsGroup = 9999
+	if *mapping.Spec.GID != gid {
+		t.Fatalf("mutating the returned RunAsGroup changed the cached mapping's GID to %d", *mapping.Spec.GID)
+	}
+}
+
+func TestSetUIDLeavesRunAsGroupUnsetWhenGIDNil(t *testing.T) {
+	mapping := &webhooksv1.UidMapping{Spec: webhooksv1.UidMappingSpec{UID: 1000}}
+
+	result := setUID(testMountHomeDirectory(), nil, mapping)
+	if result.RunAsGroup != nil {
+		t.Fatalf("RunAsGroup = %v, want nil when GID is unset", *result.RunAsGroup)
+	}
+}
+
+func TestSetUIDCopiesSupplementalGroupsRatherThanAliasing(t *testing.T) {
+	mapping := &webhooksv1.UidMapping{
+		Spec: webhooksv1.UidMappingSpec{UID: 1000, SupplementalGroups: []int64{100, 200}},
+	}
+
+	result := setUID(testMountHomeDirectory(), nil, mapping)
+	if len(result.SupplementalGroups) != 2 || result.SupplementalGroups[0] != 100 || result.SupplementalGroups[1] != 200 {
+		t.Fatalf("SupplementalGroups = %v, want [100 200]", result.SupplementalGroups)
+	}
+
+	result.SupplementalGroups[0] = 999
+	if mapping.Spec.SupplementalGroups[0] != 100 {
+		t.Fatalf("mutating the returned slice changed the cached mapping's SupplementalGroups to %v", mapping.Spec.SupplementalGroups)
+	}
+}
+
+func TestSetUIDCopiesFSGroupRatherThanAliasing(t *testing.T) {
+	fsGroup := int64(3000)
+	mapping := &webhooksv1.UidMapping{
+		Spec: webhooksv1.UidMappingSpec{UID: 1000, FSGroup: &fsGroup},
+	}
+
+	result := setUID(testMountHomeDirectory(), nil, mapping)
+	if result.FSGroup == mapping.Spec.FSGroup {
+		t.Fatalf("FSGroup aliases mapping.Spec.FSGroup instead of a fresh pointer")
+	}
+
+	*result.FSGroup = 9999
+	if *mapping.Spec.FSGroup != fsGroup {
+		t.Fatalf("mutating the returned FSGroup changed the cached mapping's FSGroup to %d", *mapping.Spec.FSGroup)
+	}
+}
+
+func TestSetUIDInitializesNilSecurityContext(t *testing.T) {
+	mapping := &webhooksv1.UidMapping{Spec: webhooksv1.UidMappingSpec{UID: 1000}}
+
+	result := setUID(testMountHomeDirectory(), nil, mapping)
+	if result == nil {
+		t.Fatal("setUID returned nil for a nil existing SecurityContext")
+	}
+	if result.RunAsUser == nil || *result.RunAsUser != 1000 {
+		t.Fatalf("RunAsUser = %v, want 1000", result.RunAsUser)
+	}
+}
+
+func TestInjectHomeVolumeHostPath(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}},
+		},
+	}
+
+	if err := injectHomeVolume(pod, "my-sa", defaultHomeDirectoryConfig); err != nil {
+		t.Fatalf("injectHomeVolume: %s", err)
+	}
+
+	if len(pod.Spec.Volumes) != 1 {
+		t.Fatalf("expected one volume, got %d", len(pod.Spec.Volumes))
+	}
+	volume := pod.Spec.Volumes[0]
+	if volume.HostPath == nil || volume.HostPath.Path != "/home/my-sa" {
+		t.Fatalf("volume = %+v, want hostPath /home/my-sa", volume)
+	}
+
+	if len(pod.Spec.Containers[0].VolumeMounts) != 1 {
+		t.Fatalf("expected one volume mount, got %d", len(pod.Spec.Containers[0].VolumeMounts))
+	}
+	mount := pod.Spec.Containers[0].VolumeMounts[0]
+	if mount.MountPath != "/home/my-sa" {
+		t.Fatalf("mount path = %q, want /home/my-sa", mount.MountPath)
+	}
+}
+
+func TestInjectHomeVolumeSkipsOptedOutPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{defaultHomeDirectoryConfig.OptOutAnnotation: "true"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+	}
+
+	if err := injectHomeVolume(pod, "my-sa", defaultHomeDirectoryConfig); err != nil {
+		t.Fatalf("injectHomeVolume: %s", err)
+	}
+
+	if len(pod.Spec.Volumes) != 0 {
+		t.Fatalf("expected no volumes for an opted-out pod, got %d", len(pod.Spec.Volumes))
+	}
+}
+
+func TestInjectHomeVolumeSkipsExistingMount(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:         "app",
+				VolumeMounts: []corev1.VolumeMount{{Name: "other", MountPath: "/home/my-sa"}},
+			}},
+		},
+	}
+
+	if err := injectHomeVolume(pod, "my-sa", defaultHomeDirectoryConfig); err != nil {
+		t.Fatalf("injectHomeVolume: %s", err)
+	}
+
+	if len(pod.Spec.Containers[0].VolumeMounts) != 1 {
+		t.Fatalf("expected the pre-existing mount at that path to be left alone, got %+v", pod.Spec.Containers[0].VolumeMounts)
+	}
+}
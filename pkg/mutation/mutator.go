@@ -0,0 +1,17 @@
+package mutation
+
+import (
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podMutator is implemented by every mutation the webhook can apply to a
+// pod during admission. Mutators are run in an ordered Chain; each receives
+// the pod as mutated by the mutators before it.
+type podMutator interface {
+	// Name returns the mutator's short name, used to select it in the
+	// chain's configuration.
+	Name() string
+	// Mutate returns a new pod with this mutator's rules applied.
+	Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRequest) (*corev1.Pod, error)
+}
@@ -0,0 +1,71 @@
+package mutation
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lucamiano/Webhooks/pkg/kube/cache"
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
+
+// namespaceSharingEnforcer toggles HostPID, HostIPC and
+// ShareProcessNamespace on the pod according to the requesting
+// ServiceAccount's UidMapping policy.
+type namespaceSharingEnforcer struct {
+	Logger    logrus.FieldLogger
+	Cache     *cache.UidMappingCache
+	Validator *validation.Validator
+}
+
+// namespaceSharingEnforcer implements the podMutator interface
+var _ podMutator = (*namespaceSharingEnforcer)(nil)
+
+// Name returns the namespaceSharingEnforcer short name
+func (nse namespaceSharingEnforcer) Name() string {
+	return "namespace_sharing_enforcer"
+}
+
+// Mutate returns a new pod with HostPID/HostIPC/ShareProcessNamespace set
+// according to the requesting ServiceAccount's UidMapping, if it configures
+// them.
+func (nse namespaceSharingEnforcer) Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRequest) (*corev1.Pod, error) {
+	nse.Logger = nse.Logger.WithField("mutation", nse.Name())
+	mpod := pod.DeepCopy()
+
+	namespace, serviceAccount := getServiceAccount(nse.Logger, a)
+	if serviceAccount == "" {
+		return mpod, nil
+	}
+
+	mapping, err := nse.Cache.Get(namespace, serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to look up UidMapping: %s\n", err)
+	}
+	if mapping == nil {
+		return mpod, nil
+	}
+
+	if !authorizedForUidMapping(nse.Validator, nse.Logger, a.UserInfo, namespace, serviceAccount, mapping.Name) {
+		return mpod, nil
+	}
+
+	if mapping.Spec.HostPID != nil {
+		mpod.Spec.HostPID = *mapping.Spec.HostPID
+	}
+	if mapping.Spec.HostIPC != nil {
+		mpod.Spec.HostIPC = *mapping.Spec.HostIPC
+	}
+	// mapping is read from the shared informer cache and must be treated
+	// as read-only, so the pointer is copied rather than aliased into the
+	// mutated pod.
+	if mapping.Spec.ShareProcessNamespace != nil {
+		shareProcessNamespace := *mapping.Spec.ShareProcessNamespace
+		mpod.Spec.ShareProcessNamespace = &shareProcessNamespace
+	}
+
+	nse.Logger.Infof("Applying namespace sharing policy for ServiceAccount %s in namespace %s", serviceAccount, namespace)
+	return mpod, nil
+}
@@ -0,0 +1,102 @@
+package mutation
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PatchOperation is a single RFC 6902 JSON Patch operation, as expected in
+// an AdmissionResponse's Patch field.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// diffPatch computes the JSONPatch fragment that turns orig into mutated.
+// Maps are diffed key by key (in sorted order, so the result is
+// deterministic); any other value that differs - including slices, which
+// are compared and replaced as a whole - produces a single add/replace/
+// remove operation. This is sufficient for the field-level changes pod
+// mutators make (security context, volumes, annotations, ...).
+func diffPatch(orig, mutated interface{}) ([]PatchOperation, error) {
+	origMap, err := toGenericJSON(orig)
+	if err != nil {
+		return nil, err
+	}
+	mutatedMap, err := toGenericJSON(mutated)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []PatchOperation
+	diffValues("", origMap, mutatedMap, &ops)
+	return ops, nil
+}
+
+func toGenericJSON(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+func diffValues(path string, orig, mutated interface{}, ops *[]PatchOperation) {
+	mutatedMap, mutatedIsMap := mutated.(map[string]interface{})
+	origMap, origIsMap := orig.(map[string]interface{})
+
+	if mutatedIsMap && origIsMap {
+		keys := make([]string, 0, len(mutatedMap))
+		for k := range mutatedMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			childPath := path + "/" + escapeJSONPointer(k)
+			if origVal, exists := origMap[k]; exists {
+				diffValues(childPath, origVal, mutatedMap[k], ops)
+			} else {
+				*ops = append(*ops, PatchOperation{Op: "add", Path: childPath, Value: mutatedMap[k]})
+			}
+		}
+
+		removedKeys := make([]string, 0)
+		for k := range origMap {
+			if _, exists := mutatedMap[k]; !exists {
+				removedKeys = append(removedKeys, k)
+			}
+		}
+		sort.Strings(removedKeys)
+		for _, k := range removedKeys {
+			*ops = append(*ops, PatchOperation{Op: "remove", Path: path + "/" + escapeJSONPointer(k)})
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(orig, mutated) {
+		op := "replace"
+		if orig == nil {
+			op = "add"
+		}
+		if path == "" {
+			path = "/"
+		}
+		*ops = append(*ops, PatchOperation{Op: op, Path: path, Value: mutated})
+	}
+}
+
+// escapeJSONPointer escapes a JSON object key for use as a path segment,
+// per RFC 6901.
+func escapeJSONPointer(key string) string {
+	key = strings.ReplaceAll(key, "~", "~0")
+	key = strings.ReplaceAll(key, "/", "~1")
+	return key
+}
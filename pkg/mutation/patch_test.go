@@ -0,0 +1,180 @@
+package mutation
+
+import (
+	"testing"
+)
+
+func opsByPath(ops []PatchOperation) map[string]PatchOperation {
+	byPath := make(map[string]PatchOperation, len(ops))
+	for _, op := range ops {
+		byPath[op.Path] = op
+	}
+	return byPath
+}
+
+func opPaths(ops []PatchOperation) []string {
+	paths := make([]string, 0, len(ops))
+	for _, op := range ops {
+		paths = append(paths, op.Path)
+	}
+	return paths
+}
+
+func TestDiffPatchAddsMissingField(t *testing.T) {
+	type obj struct {
+		A string `json:"a"`
+		B string `json:"b,omitempty"`
+	}
+
+	ops, err := diffPatch(obj{A: "x"}, obj{A: "x", B: "y"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	byPath := opsByPath(ops)
+	op, ok := byPath["/b"]
+	if !ok {
+		t.Fatalf("expected an op at /b, got %+v", ops)
+	}
+	if op.Op != "add" {
+		t.Errorf("op at /b = %q, want add", op.Op)
+	}
+	if op.Value != "y" {
+		t.Errorf("value at /b = %v, want y", op.Value)
+	}
+}
+
+func TestDiffPatchReplacesChangedField(t *testing.T) {
+	type obj struct {
+		A string `json:"a"`
+	}
+
+	ops, err := diffPatch(obj{A: "x"}, obj{A: "z"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	byPath := opsByPath(ops)
+	op, ok := byPath["/a"]
+	if !ok {
+		t.Fatalf("expected an op at /a, got %+v", ops)
+	}
+	if op.Op != "replace" {
+		t.Errorf("op at /a = %q, want replace", op.Op)
+	}
+}
+
+func TestDiffPatchRemovesDroppedField(t *testing.T) {
+	type obj struct {
+		A string `json:"a"`
+		B string `json:"b,omitempty"`
+	}
+
+	ops, err := diffPatch(obj{A: "x", B: "y"}, obj{A: "x"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	byPath := opsByPath(ops)
+	op, ok := byPath["/b"]
+	if !ok {
+		t.Fatalf("expected an op at /b, got %+v", ops)
+	}
+	if op.Op != "remove" {
+		t.Errorf("op at /b = %q, want remove", op.Op)
+	}
+}
+
+func TestDiffPatchReplacesArrayWhole(t *testing.T) {
+	// Arrays are compared and replaced as a whole, not diffed element by
+	// element, so a single element change still produces one "replace" at
+	// the array's own path rather than a per-index patch.
+	type obj struct {
+		Items []string `json:"items"`
+	}
+
+	ops, err := diffPatch(obj{Items: []string{"a", "b", "c"}}, obj{Items: []string{"a", "x", "c"}})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	if len(ops) != 1 {
+		t.Fatalf("expected exactly one op for a whole-array replace, got %+v", ops)
+	}
+	if ops[0].Path != "/items" || ops[0].Op != "replace" {
+		t.Fatalf("got op %+v, want replace at /items", ops[0])
+	}
+}
+
+func TestDiffPatchAddsIntoNilParent(t *testing.T) {
+	// When orig has no value at all for a nested object (nil parent), the
+	// whole subtree must be emitted as a single "add" at the parent's path,
+	// not as a "replace" (there is nothing there yet to replace) and not as
+	// per-field adds under a path that doesn't exist in orig.
+	type inner struct {
+		X string `json:"x"`
+	}
+	type obj struct {
+		Inner *inner `json:"inner,omitempty"`
+	}
+
+	ops, err := diffPatch(obj{}, obj{Inner: &inner{X: "v"}})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	byPath := opsByPath(ops)
+	op, ok := byPath["/inner"]
+	if !ok {
+		t.Fatalf("expected a single op at /inner, got %+v", ops)
+	}
+	if op.Op != "add" {
+		t.Errorf("op at /inner = %q, want add", op.Op)
+	}
+}
+
+func TestDiffPatchNoopWhenUnchanged(t *testing.T) {
+	type obj struct {
+		A string `json:"a"`
+	}
+
+	ops, err := diffPatch(obj{A: "x"}, obj{A: "x"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+	if len(ops) != 0 {
+		t.Fatalf("expected no ops for an unchanged value, got %+v", ops)
+	}
+}
+
+func TestDiffPatchIsDeterministic(t *testing.T) {
+	// diffValues walks map keys in sorted order so the same diff always
+	// produces the same op sequence; this matters because the merged patch
+	// emitted to the API server must be stable across retries.
+	type obj struct {
+		A string `json:"a,omitempty"`
+		B string `json:"b,omitempty"`
+		C string `json:"c,omitempty"`
+	}
+
+	ops1, err := diffPatch(obj{}, obj{A: "1", B: "2", C: "3"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+	ops2, err := diffPatch(obj{}, obj{A: "1", B: "2", C: "3"})
+	if err != nil {
+		t.Fatalf("diffPatch: %s", err)
+	}
+
+	want := []string{"/a", "/b", "/c"}
+	got1 := opPaths(ops1)
+	got2 := opPaths(ops2)
+	if len(got1) != len(want) {
+		t.Fatalf("got paths %v, want %v", got1, want)
+	}
+	for i := range want {
+		if got1[i] != want[i] || got2[i] != want[i] {
+			t.Fatalf("non-deterministic or wrong order: run1=%v run2=%v want=%v", got1, got2, want)
+		}
+	}
+}
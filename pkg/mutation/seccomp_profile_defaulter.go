@@ -0,0 +1,69 @@
+package mutation
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lucamiano/Webhooks/pkg/kube/cache"
+	"github.com/lucamiano/Webhooks/pkg/validation"
+)
+
+// seccompProfileDefaulter sets the pod's SecurityContext.SeccompProfile
+// from the requesting ServiceAccount's UidMapping policy.
+type seccompProfileDefaulter struct {
+	Logger    logrus.FieldLogger
+	Cache     *cache.UidMappingCache
+	Validator *validation.Validator
+}
+
+// seccompProfileDefaulter implements the podMutator interface
+var _ podMutator = (*seccompProfileDefaulter)(nil)
+
+// Name returns the seccompProfileDefaulter short name
+func (spd seccompProfileDefaulter) Name() string {
+	return "seccomp_profile_defaulter"
+}
+
+// Mutate returns a new pod with SeccompProfile set according to the
+// requesting ServiceAccount's UidMapping, if one exists and configures it.
+func (spd seccompProfileDefaulter) Mutate(pod *corev1.Pod, a *admissionv1.AdmissionRequest) (*corev1.Pod, error) {
+	spd.Logger = spd.Logger.WithField("mutation", spd.Name())
+	mpod := pod.DeepCopy()
+
+	namespace, serviceAccount := getServiceAccount(spd.Logger, a)
+	if serviceAccount == "" {
+		return mpod, nil
+	}
+
+	mapping, err := spd.Cache.Get(namespace, serviceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to look up UidMapping: %s\n", err)
+	}
+	if mapping == nil || mapping.Spec.SeccompProfile == "" {
+		return mpod, nil
+	}
+
+	if !authorizedForUidMapping(spd.Validator, spd.Logger, a.UserInfo, namespace, serviceAccount, mapping.Name) {
+		return mpod, nil
+	}
+
+	if mpod.Spec.SecurityContext == nil {
+		mpod.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	// mapping is read from the shared informer cache and must be treated as
+	// read-only, so the localhost profile name is copied rather than
+	// aliased into the mutated pod.
+	profile := &corev1.SeccompProfile{Type: corev1.SeccompProfileType(mapping.Spec.SeccompProfile)}
+	if profile.Type == corev1.SeccompProfileTypeLocalhost {
+		localhostProfile := mapping.Spec.SeccompLocalhostProfile
+		profile.LocalhostProfile = &localhostProfile
+	}
+	mpod.Spec.SecurityContext.SeccompProfile = profile
+
+	spd.Logger.Infof("Applying seccomp profile %s for ServiceAccount %s in namespace %s", mapping.Spec.SeccompProfile, serviceAccount, namespace)
+	return mpod, nil
+}
@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	rest "k8s.io/client-go/rest"
+
+	webhooksv1 "github.com/lucamiano/Webhooks/pkg/apis/webhooks/v1"
+)
+
+// positiveReviewTTL bounds how long a successful SubjectAccessReview is
+// trusted before the webhook asks the API server again.
+const positiveReviewTTL = 30 * time.Second
+
+// cacheEntry records a cached positive review and when it expires.
+type cacheEntry struct {
+	expiresAt time.Time
+}
+
+// Validator confirms, via a SubjectAccessReview against the API server,
+// that the identity an AdmissionRequest carries is genuinely authorized to
+// receive a UID mapping, closing the trust gap where any client capable of
+// impersonating a ServiceAccount's UserInfo could otherwise drive a UID
+// assignment. AdmissionRequests don't carry the requester's raw bearer
+// token, so a SubjectAccessReview (built from the already-authenticated
+// UserInfo) is used in place of a TokenReview, mirroring how upstream
+// Kubernetes auth e2e tests exercise authorization.
+type Validator struct {
+	Logger logrus.FieldLogger
+
+	client kubernetes.Interface
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewValidator builds the in-cluster clientset once at webhook startup.
+func NewValidator(logger logrus.FieldLogger) (*Validator, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{
+		Logger: logger,
+		client: client,
+		cache:  make(map[string]cacheEntry),
+	}, nil
+}
+
+// Authorize reports whether userInfo is allowed to perform verb on the
+// named resource (in the webhooks.example.com group) in namespace. name
+// must be the specific object being applied (e.g. the resolved UidMapping's
+// name) so the review binds the requester to that object rather than
+// granting a namespace-wide "use uidmappings" permission. Positive results
+// are cached for positiveReviewTTL to avoid a round trip on every admission
+// request for the same identity/object.
+func (v *Validator) Authorize(ctx context.Context, userInfo authenticationv1.UserInfo, namespace, resource, name, verb string) (bool, error) {
+	cacheKey := namespace + "/" + name + "/" + userInfo.Username + "/" + verb + "/" + resource
+
+	v.mu.Lock()
+	entry, ok := v.cache[cacheKey]
+	v.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return true, nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for k, val := range userInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(val)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     webhooksv1.GroupName,
+				Resource:  resource,
+				Name:      name,
+			},
+		},
+	}
+
+	result, err := v.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if !result.Status.Allowed {
+		return false, nil
+	}
+
+	v.mu.Lock()
+	v.cache[cacheKey] = cacheEntry{expiresAt: time.Now().Add(positiveReviewTTL)}
+	v.mu.Unlock()
+
+	return true, nil
+}